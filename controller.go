@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	defaultNukeAnnotation = "kubenukem.xrstf.de/nuke-on-delete"
+	// customResourceCleanupFinalizer is put on every CRD by the apiextensions
+	// apiserver itself and is what actually deletes the CRs when the CRD is
+	// deleted.
+	customResourceCleanupFinalizer = "customresourcecleanup.apiextensions.k8s.io"
+)
+
+// runController starts kubenukem as a long-lived controller that watches
+// CRDs and nukes their stuck custom resources automatically, instead of
+// requiring an operator to invoke the CLI by hand.
+func runController(args []string) error {
+	fs := pflag.NewFlagSet("controller", pflag.ExitOnError)
+
+	kubeconfig := ""
+	verboseLog := false
+	annotation := defaultNukeAnnotation
+	gracePeriod := 2 * time.Minute
+	timeout := 30 * time.Second
+	pollInterval := 1 * time.Second
+
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "kubeconfig file to use (uses $KUBECONFIG by default)")
+	fs.BoolVarP(&verboseLog, "verbose", "v", verboseLog, "enable more verbose logging")
+	fs.StringVar(&annotation, "annotation", annotation, "annotation that opts a CRD into automatic nuking once stuck terminating")
+	fs.DurationVar(&gracePeriod, "grace-period", gracePeriod, "how long a CRD may be stuck in Terminating before its stuck custom resources get nuked")
+	fs.DurationVar(&timeout, "timeout", timeout, "how long to wait for each custom resource to actually disappear after its finalizers are stripped")
+	fs.DurationVar(&pollInterval, "poll-interval", pollInterval, "how often to poll the API server while waiting for a custom resource to disappear")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: time.RFC1123,
+	})
+
+	if verboseLog {
+		log.SetLevel(logrus.DebugLevel)
+	}
+
+	ctrl.SetLogger(zap.New(zap.UseDevMode(verboseLog)))
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kube config: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add apiextensions/v1 to scheme: %w", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed to add core/v1 to scheme: %w", err)
+	}
+
+	mgr, err := ctrl.NewManager(config, ctrl.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create manager: %w", err)
+	}
+
+	reconciler := &nukeOnDeleteReconciler{
+		client:      mgr.GetClient(),
+		log:         log,
+		annotation:  annotation,
+		gracePeriod: gracePeriod,
+		opts: &nukeOptions{
+			Timeout:      timeout,
+			PollInterval: pollInterval,
+		},
+	}
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&apiextensionsv1.CustomResourceDefinition{}).
+		Complete(reconciler); err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	log.Info("Starting controller…")
+
+	return mgr.Start(signals.SetupSignalHandler())
+}
+
+// nukeOnDeleteReconciler watches CustomResourceDefinition objects. Once one
+// is annotated with the nuke-on-delete annotation and has been stuck in
+// Terminating behind the apiextensions cleanup finalizer for longer than
+// gracePeriod, it strips the finalizers of its remaining custom resources
+// using the same logic the one-shot CLI uses.
+type nukeOnDeleteReconciler struct {
+	client      ctrlruntimeclient.Client
+	log         logrus.FieldLogger
+	annotation  string
+	gracePeriod time.Duration
+	opts        *nukeOptions
+}
+
+func (r *nukeOnDeleteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := r.client.Get(ctx, req.NamespacedName, crd); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, fmt.Errorf("failed to get CRD: %w", err)
+	}
+
+	if crd.Annotations[r.annotation] != "true" {
+		return reconcile.Result{}, nil
+	}
+
+	if crd.DeletionTimestamp.IsZero() || !hasFinalizer(crd.Finalizers, customResourceCleanupFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	stuckFor := time.Since(crd.DeletionTimestamp.Time)
+	if stuckFor < r.gracePeriod {
+		return reconcile.Result{RequeueAfter: r.gracePeriod - stuckFor}, nil
+	}
+
+	crdLog := r.log.WithField("crd", crd.Name)
+	crdLog.Warnf("CRD stuck in Terminating for more than %s, nuking its stuck resources.", r.gracePeriod)
+
+	if _, _, err := removeResources(ctx, crdLog, r.client, crd, r.opts); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to remove stuck resources: %w", err)
+	}
+
+	return reconcile.Result{RequeueAfter: r.gracePeriod}, nil
+}
+
+func hasFinalizer(finalizers []string, name string) bool {
+	for _, f := range finalizers {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -7,16 +7,22 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
@@ -43,15 +49,47 @@ func printVersion() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "controller" {
+		if err := runController(os.Args[2:]); err != nil {
+			logrus.Fatalf("Controller failed: %v", err)
+		}
+
+		return
+	}
+
+	runNuke()
+}
+
+func runNuke() {
 	ctx := signals.SetupSignalHandler()
 
 	kubeconfig := ""
 	verboseLog := false
 	version := false
+	selector := ""
+	fieldSelector := ""
+	dryRun := false
+	output := "table"
+	timeout := 30 * time.Second
+	pollInterval := 1 * time.Second
+	stripOwnerRefs := false
+	recursive := false
+	apiVersion := ""
+	concurrency := 10
 
 	pflag.StringVar(&kubeconfig, "kubeconfig", "", "kubeconfig file to use (uses $KUBECONFIG by default)")
 	pflag.BoolVarP(&verboseLog, "verbose", "v", verboseLog, "enable more verbose logging")
 	pflag.BoolVarP(&version, "version", "V", version, "show version info and exit immediately")
+	pflag.StringVarP(&selector, "selector", "l", "", "label selector to scope the custom resources to nuke; if set, the CRD itself is left in place and only matching custom resources are touched")
+	pflag.StringVar(&fieldSelector, "field-selector", "", "field selector to scope the custom resources to nuke; if set, the CRD itself is left in place and only matching custom resources are touched")
+	pflag.BoolVar(&dryRun, "dry-run", dryRun, "only report what would be nuked, without deleting or patching anything")
+	pflag.StringVarP(&output, "output", "o", output, "output format for --dry-run, one of: table, json, yaml")
+	pflag.DurationVar(&timeout, "timeout", timeout, "how long to wait for each custom resource to actually disappear after its finalizers are stripped")
+	pflag.DurationVar(&pollInterval, "poll-interval", pollInterval, "how often to poll the API server while waiting for a custom resource to disappear")
+	pflag.BoolVar(&stripOwnerRefs, "strip-owner-refs", stripOwnerRefs, "if a resource is stuck because of a blocking owner reference, patch it away instead of leaving the resource stuck")
+	pflag.BoolVar(&recursive, "recursive", recursive, "if a resource is stuck because of a blocking owner reference, recursively nuke that specific owning resource (not its whole CRD) instead of leaving the resource stuck")
+	pflag.StringVar(&apiVersion, "api-version", apiVersion, "pin the CRD version to list and patch custom resources under, e.g. when a conversion webhook is what is broken")
+	pflag.IntVar(&concurrency, "concurrency", concurrency, "how many custom resources to nuke in parallel")
 	pflag.Parse()
 
 	if version {
@@ -92,16 +130,60 @@ func main() {
 		log.Fatalf("Failed to add apiextensions/v1 to scheme: %v", err)
 	}
 
+	opts := &nukeOptions{}
+
+	if selector != "" {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			log.Fatalf("Invalid --selector: %v", err)
+		}
+		opts.LabelSelector = sel
+	}
+
+	if fieldSelector != "" {
+		sel, err := fields.ParseSelector(fieldSelector)
+		if err != nil {
+			log.Fatalf("Invalid --field-selector: %v", err)
+		}
+		opts.FieldSelector = sel
+	}
+
+	opts.DryRun = dryRun
+	opts.Timeout = timeout
+	opts.PollInterval = pollInterval
+	opts.StripOwnerRefs = stripOwnerRefs
+	opts.Recursive = recursive
+	opts.Version = apiVersion
+	opts.Concurrency = concurrency
+
+	crdNames, err := resolveCRDNames(ctx, client, pflag.Args())
+	if err != nil {
+		log.Fatalf("Failed to resolve CRD names: %v", err)
+	}
+
 	success := false
+	reports := make([]CRDReport, 0, len(crdNames))
 
-	for _, crdName := range pflag.Args() {
-		crdName := strings.ToLower(crdName)
+	for _, crdName := range crdNames {
 		crdLog := log.WithField("crd", crdName)
 
-		if err := nukeCRD(ctx, crdLog, client, crdName); err != nil {
+		report, err := nukeCRD(ctx, crdLog, client, crdName, opts)
+		if err != nil {
 			crdLog.Errorf("Failed to nuke: %v", err)
 			success = true
 		}
+
+		if report != nil {
+			reports = append(reports, *report)
+		}
+	}
+
+	if dryRun {
+		if err := printReports(os.Stdout, output, reports); err != nil {
+			log.Fatalf("Failed to print report: %v", err)
+		}
+
+		return
 	}
 
 	if success {
@@ -111,32 +193,208 @@ func main() {
 	}
 }
 
-func nukeCRD(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, crdName string) error {
-	log.Info("Nuking…")
+// nukeOptions bundles together all the optional ways in which the nuking
+// process can be scoped or altered, so they can be threaded through the
+// call chain without every function growing its own parameter list.
+type nukeOptions struct {
+	LabelSelector  labels.Selector
+	FieldSelector  fields.Selector
+	DryRun         bool
+	Timeout        time.Duration
+	PollInterval   time.Duration
+	StripOwnerRefs bool
+	Recursive      bool
+	Version        string
+	Concurrency    int
+
+	// visited tracks "GVK/namespace/name" keys of owners already nuked by
+	// --recursive, to break cycles between CRs that own each other. It is
+	// guarded by visitedMu since --concurrency lets several resources hit it
+	// at once.
+	visitedMu sync.Mutex
+	visited   map[string]bool
+}
+
+// listOptions turns the user-provided selectors into the ListOptions
+// controller-runtime expects, in addition to whatever the caller already set
+// (e.g. a namespace).
+func (o *nukeOptions) listOptions(base *ctrlruntimeclient.ListOptions) *ctrlruntimeclient.ListOptions {
+	if base == nil {
+		base = &ctrlruntimeclient.ListOptions{}
+	}
+
+	if o != nil {
+		if o.LabelSelector != nil {
+			base.LabelSelector = o.LabelSelector
+		}
+		if o.FieldSelector != nil {
+			base.FieldSelector = o.FieldSelector
+		}
+	}
+
+	return base
+}
+
+// resolveCRDNames expands the patterns given on the command line into a
+// concrete, lower-cased list of CRD names. A pattern is treated as a glob
+// (e.g. "*.example.com") if it contains any shell glob metacharacters, or as
+// a regular expression if it is wrapped in slashes (e.g. "/^foo-.*$/").
+// Everything else is treated as a literal CRD name and is not expanded; this
+// preserves the original behavior of just trying to nuke that exact CRD,
+// including logging and skipping cleanly if it does not exist.
+func resolveCRDNames(ctx context.Context, client ctrlruntimeclient.Client, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no CRD names provided")
+	}
+
+	var (
+		literal []string
+		globby  []string
+	)
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+
+		if isCRDPattern(pattern) {
+			globby = append(globby, pattern)
+		} else {
+			literal = append(literal, pattern)
+		}
+	}
+
+	names := literal
+
+	if len(globby) > 0 {
+		crdList := &apiextensionsv1.CustomResourceDefinitionList{}
+		if err := client.List(ctx, crdList); err != nil {
+			return nil, fmt.Errorf("failed to list CRDs: %w", err)
+		}
+
+		seen := map[string]bool{}
+		for _, name := range names {
+			seen[name] = true
+		}
+
+		for _, crd := range crdList.Items {
+			crdName := strings.ToLower(crd.Name)
+
+			for _, pattern := range globby {
+				matched, err := matchCRDPattern(pattern, crdName)
+				if err != nil {
+					return nil, err
+				}
+
+				if matched && !seen[crdName] {
+					seen[crdName] = true
+					names = append(names, crdName)
+				}
+			}
+		}
+	}
+
+	return names, nil
+}
+
+func isCRDPattern(pattern string) bool {
+	if strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") && len(pattern) > 1 {
+		return true
+	}
+
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func matchCRDPattern(pattern, crdName string) (bool, error) {
+	if strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") && len(pattern) > 1 {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+
+		return re.MatchString(crdName), nil
+	}
+
+	matched, err := filepath.Match(pattern, crdName)
+	if err != nil {
+		return false, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+	}
+
+	return matched, nil
+}
+
+func nukeCRD(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, crdName string, opts *nukeOptions) (*CRDReport, error) {
+	// a selector scopes this run down to specific custom resources; in that
+	// case the CRD itself is left alone and only the matching CRs are acted
+	// upon, so the same CRD can be nuked again for a different subset later.
+	scoped := opts.LabelSelector != nil || opts.FieldSelector != nil
+
+	if opts.DryRun {
+		log.Info("Would nuke…")
+	} else if scoped {
+		log.Info("Nuking matching custom resources, leaving the CRD itself in place…")
+	} else {
+		log.Info("Nuking…")
+	}
 
 	// fetch the CRD
 	crd := &apiextensionsv1.CustomResourceDefinition{}
 	if err := client.Get(ctx, types.NamespacedName{Name: crdName}, crd); err != nil {
 		if kerrors.IsNotFound(err) {
 			log.Debug("CRD does not exist.")
-			return nil
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to retrieve CRD: %w", err)
+	}
+
+	if opts.DryRun {
+		resources, _, err := removeResources(ctx, log, client, crd, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &CRDReport{CRD: crdName, Resources: resources}, nil
+	}
+
+	if scoped {
+		_, stuck, err := removeResources(ctx, log, client, crd, opts)
+		if err != nil {
+			return nil, err
 		}
 
-		return fmt.Errorf("failed to retrieve CRD: %w", err)
+		if len(stuck) > 0 {
+			for _, res := range stuck {
+				log.WithField("resource", resourceIdent(res.Namespace, res.Name)).Warnf("Still stuck: %s", res.Reason)
+			}
+
+			log.Warnf("%d resource(s) did not disappear within the timeout.", len(stuck))
+		}
+
+		return nil, nil
 	}
 
 	// delete it, this will get rid of all CRs with no finalizers, i.e. less work for us to do
 	if err := client.Delete(ctx, crd); err != nil {
-		return fmt.Errorf("failed to delete CRD resource: %w", err)
+		return nil, fmt.Errorf("failed to delete CRD resource: %w", err)
 	}
 
-	// remove stuck resources
-	if err := removeResources(ctx, log, client, crd); err != nil {
-		return err
+	// remove stuck resources, waiting for each of them to actually disappear
+	_, stuck, err := removeResources(ctx, log, client, crd, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// check if the CRD is gone
-	err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+	if len(stuck) > 0 {
+		for _, res := range stuck {
+			log.WithField("resource", resourceIdent(res.Namespace, res.Name)).Warnf("Still stuck: %s", res.Reason)
+		}
+
+		log.Warnf("CRD still exists, %d resource(s) did not disappear within the timeout.", len(stuck))
+
+		return nil, nil
+	}
+
+	// one last check in case the CRD itself is held up by something other than its CRs
+	pollErr := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
 		crd := &apiextensionsv1.CustomResourceDefinition{}
 		err := client.Get(ctx, types.NamespacedName{Name: crdName}, crd)
 		if err == nil {
@@ -148,81 +406,291 @@ func nukeCRD(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclie
 
 		return false, err
 	})
-	if wait.Interrupted(err) {
+	if wait.Interrupted(pollErr) {
 		log.Warn("CRD still exists, some resources might be blocked by owner references to them.")
-	} else if err != nil {
-		return fmt.Errorf("failed to check final CRD existence: %w", err)
+	} else if pollErr != nil {
+		return nil, fmt.Errorf("failed to check final CRD existence: %w", pollErr)
 	}
 
-	return nil
+	return nil, nil
 }
 
-func removeResources(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, crd *apiextensionsv1.CustomResourceDefinition) error {
+func removeResources(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, crd *apiextensionsv1.CustomResourceDefinition, opts *nukeOptions) ([]ResourceReport, []StuckResource, error) {
 	if crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
 		nsList := &corev1.NamespaceList{}
 		if err := client.List(ctx, nsList); err != nil && !kerrors.IsNotFound(err) {
-			return fmt.Errorf("failed to list namespaces: %w", err)
+			return nil, nil, fmt.Errorf("failed to list namespaces: %w", err)
 		}
 
+		var (
+			reports []ResourceReport
+			stuck   []StuckResource
+		)
+
 		for _, namespace := range nsList.Items {
-			opt := &ctrlruntimeclient.ListOptions{
+			listOpt := opts.listOptions(&ctrlruntimeclient.ListOptions{
 				Namespace: namespace.Name,
-			}
+			})
 
-			if err := removeResourcesWithOpts(ctx, log, client, crd, opt); err != nil {
-				return err
+			nsReports, nsStuck, err := removeResourcesWithOpts(ctx, log, client, crd, opts, listOpt)
+			if err != nil {
+				return nil, nil, err
 			}
+
+			reports = append(reports, nsReports...)
+			stuck = append(stuck, nsStuck...)
 		}
 
-		return nil
+		return reports, stuck, nil
 	}
 
-	return removeResourcesWithOpts(ctx, log, client, crd)
+	return removeResourcesWithOpts(ctx, log, client, crd, opts, opts.listOptions(nil))
 }
 
-func removeResourcesWithOpts(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, crd *apiextensionsv1.CustomResourceDefinition, opts ...ctrlruntimeclient.ListOption) error {
-	apiVersion, err := getAPIVersion(crd)
+// resourcesPageSize bounds how many objects are pulled into memory per List
+// call; the rest is streamed page by page via the continue token instead of
+// loading an entire, possibly huge, CR list at once.
+const resourcesPageSize = 500
+
+func removeResourcesWithOpts(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, crd *apiextensionsv1.CustomResourceDefinition, opts *nukeOptions, listOpts ...ctrlruntimeclient.ListOption) ([]ResourceReport, []StuckResource, error) {
+	apiVersions, err := getAPIVersions(crd, opts.Version)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	objectList := &unstructured.UnstructuredList{}
-	objectList.SetAPIVersion(apiVersion)
-	objectList.SetKind(crd.Spec.Names.Kind)
+	var (
+		reports       []ResourceReport
+		stuck         []StuckResource
+		apiVersion    string
+		continueToken string
+	)
+
+	for {
+		page := &unstructured.UnstructuredList{}
+		page.SetKind(crd.Spec.Names.Kind)
 
-	if err := client.List(ctx, objectList, opts...); err != nil && !kerrors.IsNotFound(err) {
-		return fmt.Errorf("failed to list objects: %w", err)
+		pageOpts := append(append([]ctrlruntimeclient.ListOption{}, listOpts...), ctrlruntimeclient.Limit(resourcesPageSize))
+		if continueToken != "" {
+			pageOpts = append(pageOpts, ctrlruntimeclient.Continue(continueToken))
+		}
+
+		if apiVersion == "" {
+			// probe the served versions once, on the very first page; once we
+			// know which version works we keep using it for the rest of the
+			// pagination.
+			var listErr error
+
+			for _, v := range apiVersions {
+				page = &unstructured.UnstructuredList{}
+				page.SetAPIVersion(v)
+				page.SetKind(crd.Spec.Names.Kind)
+
+				listErr = client.List(ctx, page, pageOpts...)
+				if listErr == nil || kerrors.IsNotFound(listErr) {
+					apiVersion = v
+					listErr = nil
+					break
+				}
+
+				log.WithField("apiVersion", v).Warnf("Failed to list objects, falling back to next served version: %v", listErr)
+			}
+
+			if listErr != nil {
+				return nil, nil, fmt.Errorf("failed to list objects under any served version: %w", listErr)
+			}
+		} else {
+			page.SetAPIVersion(apiVersion)
+
+			if err := client.List(ctx, page, pageOpts...); err != nil && !kerrors.IsNotFound(err) {
+				return nil, nil, fmt.Errorf("failed to list objects: %w", err)
+			}
+		}
+
+		pageReports, pageStuck := processResources(ctx, log, client, page.Items, opts)
+		reports = append(reports, pageReports...)
+		stuck = append(stuck, pageStuck...)
+
+		continueToken = page.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	return reports, stuck, nil
+}
+
+// processResources strips finalizers from the given objects, up to
+// opts.Concurrency at a time, and waits for each to actually disappear.
+// Objects that have no finalizers are skipped and not waited for. It
+// never aborts early on a single object's error; instead that object is
+// added to the returned stuck list so one bad apple does not stop the rest
+// of a large CRD from being cleaned up.
+func processResources(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, objs []unstructured.Unstructured, opts *nukeOptions) ([]ResourceReport, []StuckResource) {
+	if opts.DryRun {
+		reports := make([]ResourceReport, 0, len(objs))
+
+		for _, obj := range objs {
+			reports = append(reports, ResourceReport{
+				Namespace:       obj.GetNamespace(),
+				Name:            obj.GetName(),
+				Finalizers:      obj.GetFinalizers(),
+				OwnerReferences: obj.GetOwnerReferences(),
+			})
+		}
+
+		return reports, nil
 	}
 
-	for _, obj := range objectList.Items {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+
+	var (
+		mu    sync.Mutex
+		stuck []StuckResource
+	)
+
+	for _, obj := range objs {
+		obj := obj
+
 		// this should not happen, unless an ownerRef with blockOwnerDeletion is in place
 		if len(obj.GetFinalizers()) == 0 {
 			continue
 		}
 
-		objIdent := obj.GetName()
-		if ns := obj.GetNamespace(); len(ns) > 0 {
-			objIdent = fmt.Sprintf("%s/%s", ns, objIdent)
+		g.Go(func() error {
+			if res, ok := nukeResource(gctx, log, client, obj, opts); ok {
+				mu.Lock()
+				stuck = append(stuck, res)
+				mu.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	// errors are aggregated into `stuck` above, the group itself never fails
+	_ = g.Wait()
+
+	return nil, stuck
+}
+
+// nukeResource strips the finalizers of a single custom resource and waits
+// for it to disappear. It returns the StuckResource describing why it is
+// still around, and true, if its finalizers could not be stripped or it
+// didn't go away in time.
+func nukeResource(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, obj unstructured.Unstructured, opts *nukeOptions) (StuckResource, bool) {
+	objIdent := resourceIdent(obj.GetNamespace(), obj.GetName())
+
+	log.WithField("resource", objIdent).Debug("Nuking…")
+
+	oldObj := obj.DeepCopy()
+	obj.SetFinalizers(nil)
+
+	if err := client.Patch(ctx, &obj, ctrlruntimeclient.MergeFrom(oldObj)); err != nil {
+		log.WithField("resource", objIdent).Errorf("Failed to strip finalizers: %v", err)
+
+		return StuckResource{
+			Namespace:       obj.GetNamespace(),
+			Name:            obj.GetName(),
+			OwnerReferences: obj.GetOwnerReferences(),
+			Reason:          fmt.Sprintf("failed to patch: %v", err),
+		}, true
+	}
+
+	err := DeleteAndWait(ctx, client, &obj, opts.Timeout, opts.PollInterval)
+	if err != nil {
+		handled, handleErr := handleBlockingOwnerRefs(ctx, log, client, &obj, opts)
+		if handleErr != nil {
+			log.WithField("resource", objIdent).Errorf("Failed to handle blocking owner references: %v", handleErr)
+		}
+
+		if handled {
+			err = DeleteAndWait(ctx, client, &obj, opts.Timeout, opts.PollInterval)
 		}
+	}
 
-		log.WithField("resource", objIdent).Debug("Nuking…")
+	if err != nil {
+		return StuckResource{
+			Namespace:       obj.GetNamespace(),
+			Name:            obj.GetName(),
+			OwnerReferences: obj.GetOwnerReferences(),
+			Reason:          stuckReason(obj),
+		}, true
+	}
+
+	return StuckResource{}, false
+}
 
-		oldObj := obj.DeepCopy()
-		obj.SetFinalizers(nil)
-		if err := client.Patch(ctx, &obj, ctrlruntimeclient.MergeFrom(oldObj)); err != nil {
-			return fmt.Errorf("failed to delete %s: %w", objIdent, err)
+// stuckReason inspects the last observed state of a custom resource and
+// guesses why it would not disappear after its finalizers were stripped.
+func stuckReason(obj unstructured.Unstructured) string {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.BlockOwnerDeletion != nil && *ref.BlockOwnerDeletion {
+			return fmt.Sprintf("blocked by owner reference %s/%s", ref.Kind, ref.Name)
 		}
 	}
 
-	return nil
+	if len(obj.GetFinalizers()) > 0 {
+		return "finalizers could not be removed, a controller might be re-adding them"
+	}
+
+	return "deletion timestamp is set but the resource persists, an admission webhook might be rejecting it"
 }
 
-func getAPIVersion(crd *apiextensionsv1.CustomResourceDefinition) (string, error) {
+// getAPIVersions returns the served versions of crd to try listing CRs
+// under, in priority order. If pinned is set, it is the only version
+// considered, whether or not it is served, useful when a conversion webhook is what is broken and
+// preventing listing/deletion in the first place. Otherwise the storage
+// version is tried first (this is what CRs are actually persisted as), and
+// the remaining served versions are returned as fallbacks in case listing
+// under the storage version fails, e.g. because a conversion webhook is
+// down.
+func getAPIVersions(crd *apiextensionsv1.CustomResourceDefinition, pinned string) ([]string, error) {
+	if pinned != "" {
+		for _, version := range crd.Spec.Versions {
+			if version.Name == pinned {
+				return []string{fmt.Sprintf("%s/%s", crd.Spec.Group, version.Name)}, nil
+			}
+		}
+
+		return nil, fmt.Errorf("CRD has no version %q", pinned)
+	}
+
+	var (
+		storage string
+		served  []string
+	)
+
 	for _, version := range crd.Spec.Versions {
-		if version.Served {
-			return fmt.Sprintf("%s/%s", crd.Spec.Group, version.Name), nil
+		if !version.Served {
+			continue
 		}
+
+		if version.Storage {
+			storage = version.Name
+			continue
+		}
+
+		served = append(served, version.Name)
+	}
+
+	if storage == "" && len(served) == 0 {
+		return nil, fmt.Errorf("CRD has no version marked as `served`")
+	}
+
+	versions := make([]string, 0, len(served)+1)
+	if storage != "" {
+		versions = append(versions, fmt.Sprintf("%s/%s", crd.Spec.Group, storage))
+	}
+
+	for _, name := range served {
+		versions = append(versions, fmt.Sprintf("%s/%s", crd.Spec.Group, name))
 	}
 
-	return "", fmt.Errorf("CRD has no version marked as `served`")
+	return versions, nil
 }
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// blockingOwnerReferences returns the subset of refs that actually prevent
+// the garbage collector from deleting the owned object.
+func blockingOwnerReferences(refs []metav1.OwnerReference) []metav1.OwnerReference {
+	var blocking []metav1.OwnerReference
+
+	for _, ref := range refs {
+		if ref.BlockOwnerDeletion != nil && *ref.BlockOwnerDeletion {
+			blocking = append(blocking, ref)
+		}
+	}
+
+	return blocking
+}
+
+// handleBlockingOwnerRefs is called once a CR fails to disappear even after
+// its own finalizers were removed. Depending on the configured options it
+// either strips the blocking owner references (so the garbage collector no
+// longer waits for the owner) or recursively nukes the owning resource. It
+// returns true if it took an action that is worth re-checking the resource
+// for.
+func handleBlockingOwnerRefs(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, obj *unstructured.Unstructured, opts *nukeOptions) (bool, error) {
+	blocking := blockingOwnerReferences(obj.GetOwnerReferences())
+	if len(blocking) == 0 {
+		return false, nil
+	}
+
+	switch {
+	case opts.Recursive:
+		for _, ref := range blocking {
+			if err := nukeOwner(ctx, log, client, ref, obj.GetNamespace(), opts); err != nil {
+				return false, err
+			}
+		}
+
+		return true, nil
+
+	case opts.StripOwnerRefs:
+		oldObj := obj.DeepCopy()
+		obj.SetOwnerReferences(withoutBlocking(obj.GetOwnerReferences()))
+
+		if err := client.Patch(ctx, obj, ctrlruntimeclient.MergeFrom(oldObj)); err != nil {
+			return false, fmt.Errorf("failed to strip owner references from %s: %w", resourceIdent(obj.GetNamespace(), obj.GetName()), err)
+		}
+
+		return true, nil
+
+	default:
+		return false, nil
+	}
+}
+
+func withoutBlocking(refs []metav1.OwnerReference) []metav1.OwnerReference {
+	kept := make([]metav1.OwnerReference, 0, len(refs))
+
+	for _, ref := range refs {
+		if ref.BlockOwnerDeletion != nil && *ref.BlockOwnerDeletion {
+			continue
+		}
+
+		kept = append(kept, ref)
+	}
+
+	return kept
+}
+
+// nukeOwner resolves an ownerReference to the specific object it points at,
+// via the RESTMapper, and runs the same strip-finalizers-and-wait logic
+// nukeResource uses on just that object — not on the whole CRD that defines
+// its kind, which would cascade to every other instance of that kind in the
+// cluster. A visited-set keyed by "GVK/namespace/name" is used to break
+// cycles between CRs that own each other.
+func nukeOwner(ctx context.Context, log logrus.FieldLogger, client ctrlruntimeclient.Client, ref metav1.OwnerReference, namespace string, opts *nukeOptions) error {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return fmt.Errorf("invalid ownerReference apiVersion %q: %w", ref.APIVersion, err)
+	}
+
+	mapping, err := client.RESTMapper().RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version)
+	if err != nil {
+		return fmt.Errorf("failed to resolve owner %s/%s via RESTMapper: %w", ref.Kind, ref.Name, err)
+	}
+
+	ownerNamespace := namespace
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		ownerNamespace = ""
+	}
+
+	visitKey := fmt.Sprintf("%s/%s/%s", mapping.GroupVersionKind.String(), ownerNamespace, ref.Name)
+
+	opts.visitedMu.Lock()
+	if opts.visited == nil {
+		opts.visited = map[string]bool{}
+	}
+
+	alreadyVisited := opts.visited[visitKey]
+	opts.visited[visitKey] = true
+	opts.visitedMu.Unlock()
+
+	if alreadyVisited {
+		log.WithField("owner", visitKey).Debug("Already visited this owner, skipping to avoid a cycle.")
+		return nil
+	}
+
+	owner := &unstructured.Unstructured{}
+	owner.SetGroupVersionKind(mapping.GroupVersionKind)
+
+	if err := client.Get(ctx, types.NamespacedName{Namespace: ownerNamespace, Name: ref.Name}, owner); err != nil {
+		if kerrors.IsNotFound(err) {
+			log.WithField("owner", visitKey).Debug("Owning resource is already gone.")
+			return nil
+		}
+
+		return fmt.Errorf("failed to get owner %s: %w", visitKey, err)
+	}
+
+	ownerLog := log.WithField("owner", visitKey)
+	ownerLog.Warn("Resource is blocked by this owner, nuking it directly (its CRD and sibling resources are left untouched).")
+
+	// unlike the objects nukeResource normally sees (which are already
+	// terminating because their CRD was just deleted), this owner might not
+	// be going away on its own, so it needs an explicit Delete before its
+	// finalizers are stripped and we wait for it to disappear.
+	if err := client.Delete(ctx, owner); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete owner %s: %w", visitKey, err)
+	}
+
+	if res, stuck := nukeResource(ctx, ownerLog, client, *owner, opts); stuck {
+		return fmt.Errorf("owner %s is still stuck: %s", visitKey, res.Reason)
+	}
+
+	return nil
+}
+
+func resourceIdent(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// CRDReport describes what --dry-run found for a single CRD: the CRD itself
+// (removed unless --selector or --field-selector is set) and any custom resources that would have their
+// finalizers stripped.
+type CRDReport struct {
+	CRD       string           `json:"crd"`
+	Resources []ResourceReport `json:"resources,omitempty"`
+}
+
+// ResourceReport describes a single custom resource that --dry-run found,
+// including whatever would block its normal deletion.
+type ResourceReport struct {
+	Namespace       string                  `json:"namespace,omitempty"`
+	Name            string                  `json:"name"`
+	Finalizers      []string                `json:"finalizers,omitempty"`
+	OwnerReferences []metav1.OwnerReference `json:"ownerReferences,omitempty"`
+}
+
+// StuckResource describes a custom resource that did not disappear within
+// the wait timeout even after its finalizers were stripped, and why.
+type StuckResource struct {
+	Namespace       string
+	Name            string
+	OwnerReferences []metav1.OwnerReference
+	Reason          string
+}
+
+// printReports renders the given dry-run reports to w in the requested
+// format.
+func printReports(w io.Writer, format string, reports []CRDReport) error {
+	switch format {
+	case "", "table":
+		return printReportsTable(w, reports)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(reports)
+	case "yaml":
+		out, err := yaml.Marshal(reports)
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+
+		_, err = w.Write(out)
+
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of: table, json, yaml", format)
+	}
+}
+
+func printReportsTable(w io.Writer, reports []CRDReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "CRD\tNAMESPACE\tNAME\tFINALIZERS\tOWNER REFERENCES")
+
+	for _, report := range reports {
+		if len(report.Resources) == 0 {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\n", report.CRD)
+			continue
+		}
+
+		for _, res := range report.Resources {
+			ns := res.Namespace
+			if ns == "" {
+				ns = "-"
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", report.CRD, ns, res.Name, formatFinalizers(res.Finalizers), formatOwnerReferences(res.OwnerReferences))
+		}
+	}
+
+	return tw.Flush()
+}
+
+func formatFinalizers(finalizers []string) string {
+	if len(finalizers) == 0 {
+		return "-"
+	}
+
+	return strings.Join(finalizers, ",")
+}
+
+func formatOwnerReferences(refs []metav1.OwnerReference) string {
+	if len(refs) == 0 {
+		return "-"
+	}
+
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		blocking := ""
+		if ref.BlockOwnerDeletion != nil && *ref.BlockOwnerDeletion {
+			blocking = "*"
+		}
+
+		parts = append(parts, fmt.Sprintf("%s/%s%s", ref.Kind, ref.Name, blocking))
+	}
+
+	return strings.Join(parts, ",")
+}
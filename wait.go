@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"math"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeleteAndWait polls the API server until obj is gone or timeout elapses.
+// Transient errors (anything but NotFound) do not abort the wait immediately;
+// instead the poll interval is backed off exponentially, capped at 10x the
+// configured pollInterval, so a flaky apiserver does not make us give up on
+// the very first hiccup. It issues no Delete itself; obj is expected to be
+// on its way out already.
+func DeleteAndWait(ctx context.Context, client ctrlruntimeclient.Client, obj *unstructured.Unstructured, timeout, pollInterval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+	backoff := wait.Backoff{
+		Duration: pollInterval,
+		Factor:   2.0,
+		Jitter:   0.1,
+		Steps:    math.MaxInt32,
+		Cap:      pollInterval * 10,
+	}
+
+	for {
+		current := obj.DeepCopy()
+
+		err := client.Get(ctx, key, current)
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+
+		delay := pollInterval
+		if err != nil {
+			// transient error, back off before retrying
+			delay = backoff.Step()
+		} else {
+			// remember the last observed state so the caller can explain why
+			// the resource is still stuck once the wait gives up
+			*obj = *current
+			backoff.Duration, backoff.Steps = pollInterval, math.MaxInt32
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}